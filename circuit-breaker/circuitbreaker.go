@@ -1,214 +1,338 @@
+// Package circuit_breaker implements a generic, typed circuit breaker.
+//
+// CircuitBreaker[T] wraps calls to an action that produces a typed result.
+// The pre-generics func() error signature is kept available as the
+// package-level Execute function rather than a method, since Go does not
+// allow a type to declare two methods named Execute with different
+// signatures. This is NOT a source-compatible shim: Go does not allow
+// NewCircuitBreaker(cfg) to resolve to NewCircuitBreaker[any](cfg) either,
+// so every call site must change — cb.Execute(fn) to
+// circuit_breaker.Execute(cb, fn), and NewCircuitBreaker(cfg) to
+// NewCircuitBreaker[any](cfg). Only the non-generic error-only calling
+// convention is preserved, not the ability to compile unmodified callers.
 package circuit_breaker
 
 import (
-	"container/list"
+	"context"
 	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 )
 
-type CircuitBreaker struct {
+// CircuitBreaker wraps calls to an action that produces a typed result,
+// tracking failures/slow calls in a sliding window and tripping Open when
+// thresholds are breached.
+type CircuitBreaker[T any] struct {
 	config Config
 	state  *CircuitBreakerState
 	mu     sync.Mutex
 
-	halfOpenCalls   int // Track permitted calls in half-open state
-	lastFailureTime time.Time
-	requests        *list.List
-
-	failureCount  int
-	slowCallCount int
+	halfOpenCalls     int32 // Track permitted calls in half-open state
+	halfOpenSuccesses int32 // Track successful probes in half-open state
+	lastFailureTime   time.Time
+	window            slidingWindow
 }
 
-func NewCircuitBreaker(config Config) *CircuitBreaker {
-	return &CircuitBreaker{
-		config:   config,
-		state:    &CircuitBreakerState{state: Closed},
-		requests: list.New(),
+func NewCircuitBreaker[T any](config Config) *CircuitBreaker[T] {
+	return &CircuitBreaker[T]{
+		config: config,
+		state:  &CircuitBreakerState{state: Closed},
+		window: newSlidingWindow(config),
 	}
 }
 
-// requestEntry stores whether a call was a failure or slow
-type requestEntry struct {
-	failed        bool
-	slow          bool
-	executionTime time.Time
-}
+// Execute runs action, returning its result unchanged when the breaker
+// permits the call, or a zero value and a circuit breaker error when it
+// does not.
+func (cb *CircuitBreaker[T]) Execute(action func() (T, error)) (T, error) {
+	var zero T
 
-func (cb *CircuitBreaker) Execute(action func() error) error {
 	cb.mu.Lock()
 
 	if cb.state == nil {
 		cb.mu.Unlock()
-		return errors.New("circuit breaker state is not initialized")
+		return zero, errors.New("circuit breaker state is not initialized")
 	}
 
 	// transition from Open → Half-Open if cooling time has passed
+	var transition *stateTransition
 	if cb.state.IsOpen() && time.Since(cb.lastFailureTime) >= cb.config.WaitDurationInOpenState {
 		log.Printf("%s: moving state to half-open", cb.config.Name)
-		cb.state.SetState(HalfOpen)
-		cb.halfOpenCalls = 0 // Reset allowed calls
+		t := cb.setState(HalfOpen)
+		transition = &t
+		cb.halfOpenCalls = 0     // Reset allowed calls
+		cb.halfOpenSuccesses = 0 // Reset probe successes
 	}
 
 	if cb.state.IsOpen() {
 		cb.mu.Unlock()
-		return errors.New(cb.config.Name + " :circuit breaker is in opened state")
+		cb.notifyStateChange(transition)
+		cb.recordRejected()
+		return zero, fmt.Errorf("%s: %w", cb.config.Name, ErrOpenState)
 	}
 
 	if cb.state.IsHalfOpen() {
-		if cb.halfOpenCalls >= cb.config.PermittedNumberOfCallsInHalfOpenState {
+		if cb.halfOpenCalls >= cb.config.probeNumber() {
 			cb.mu.Unlock()
-			return errors.New(cb.config.Name + " :circuit breaker half-open, no more calls allowed")
+			cb.notifyStateChange(transition)
+			cb.recordRejected()
+			return zero, fmt.Errorf("%s: %w", cb.config.Name, ErrTooManyRequests)
 		}
 		cb.halfOpenCalls++ // Count half-open calls
 	}
 
 	cb.mu.Unlock()
+	cb.notifyStateChange(transition)
 
 	start := time.Now()
-	err := action()
+	result, err := action()
 	duration := time.Since(start)
 
 	// Determine if call was slow
 	isSlow := duration >= cb.config.SlowCallDurationThreshold
 
+	if cb.config.Metrics != nil {
+		cb.config.Metrics.ObserveCallDuration(cb.config.Name, duration)
+	}
+
 	// Record success/failure/slow call status
 	cb.recordResult(err, isSlow)
 
+	return result, err
+}
+
+// stateTransition records a from→to move for notifyStateChange to announce
+// once cb.mu has been released.
+type stateTransition struct {
+	from, to State
+}
+
+// setState moves the breaker's state machine to newState and returns the
+// transition to notify. Must be called with cb.mu held: it only mutates
+// state, it never invokes hooks, so callers can defer the (potentially
+// slow, potentially reentrant) notification until after unlocking.
+func (cb *CircuitBreaker[T]) setState(newState State) stateTransition {
+	from := cb.state.state
+	cb.state.SetState(newState)
+	return stateTransition{from: from, to: newState}
+}
+
+// notifyStateChange notifies the configured metrics collector and
+// OnStateChange hook about t, if any. Must be called without cb.mu held:
+// a hook that calls back into the breaker (e.g. Execute) would deadlock on
+// the non-reentrant mutex, and a slow hook or metrics backend would
+// otherwise serialize every concurrent Execute call on this breaker.
+func (cb *CircuitBreaker[T]) notifyStateChange(t *stateTransition) {
+	if t == nil {
+		return
+	}
+	if cb.config.Metrics != nil {
+		cb.config.Metrics.SetState(cb.config.Name, t.to)
+		cb.config.Metrics.IncStateTransition(cb.config.Name, t.from, t.to)
+	}
+	if cb.config.OnStateChange != nil {
+		cb.config.OnStateChange(cb.config.Name, t.from, t.to)
+	}
+}
+
+// recordRejected notifies the configured metrics collector and OnRejected
+// hook about a call that was rejected without running.
+func (cb *CircuitBreaker[T]) recordRejected() {
+	if cb.config.Metrics != nil {
+		cb.config.Metrics.IncRejected(cb.config.Name)
+	}
+	if cb.config.OnRejected != nil {
+		cb.config.OnRejected(cb.config.Name)
+	}
+}
+
+// ExecuteWithFallback runs action and, if the breaker rejects the call or
+// action fails, invokes fallback with the resulting error so callers can
+// return a cached or degraded value instead of a bare error.
+func (cb *CircuitBreaker[T]) ExecuteWithFallback(action func() (T, error), fallback func(error) (T, error)) (T, error) {
+	result, err := cb.Execute(action)
+	if err != nil {
+		return fallback(err)
+	}
+	return result, nil
+}
+
+// Execute runs action through a CircuitBreaker[any], preserving the
+// pre-generics func() error signature for callers that don't need a typed
+// result.
+func Execute(cb *CircuitBreaker[any], action func() error) error {
+	_, err := cb.Execute(func() (any, error) {
+		return nil, action()
+	})
 	return err
 }
 
-func (cb *CircuitBreaker) recordResult(err error, isSlow bool) {
+// ExecuteContext runs action with ctx through a CircuitBreaker[any]. It
+// short-circuits with ctx.Err() if ctx is already done, and otherwise
+// passes ctx into action so cancellation can be observed mid-call. Pair it
+// with Config.FailureCondition (e.g. IgnoreContextCancellation) so a
+// context the caller canceled doesn't count toward the failure rate.
+func ExecuteContext(cb *CircuitBreaker[any], ctx context.Context, action func(context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := cb.Execute(func() (any, error) {
+		return nil, action(ctx)
+	})
+	return err
+}
+
+// IgnoreContextCancellation is a FailureCondition that excludes errors
+// caused by the caller canceling or timing out its own context from the
+// failure rate, a common source of false-positive tripping when upstream
+// clients time out.
+func IgnoreContextCancellation(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func (cb *CircuitBreaker[T]) recordResult(err error, isSlow bool) {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
 
-	cb.addRequest(err != nil, isSlow)
+	failed := cb.isFailure(err)
+	cb.window.record(failed, isSlow)
 
 	failureRate := cb.getFailureRate()
 	slowCallRate := cb.getSlowCallRate()
 
+	var transition *stateTransition
+
 	switch cb.state.state {
 	case Closed:
-		if cb.requests.Len() >= cb.config.MinimumNumberOfCalls &&
+		if cb.window.len() >= cb.config.MinimumNumberOfCalls &&
 			(failureRate >= cb.config.FailureRateThreshold || slowCallRate >= cb.config.SlowCallRateThreshold) {
-			cb.state.SetState(Open)
+			t := cb.setState(Open)
+			transition = &t
 			cb.lastFailureTime = time.Now()
 			log.Printf("%s: moving state to open", cb.config.Name)
 		}
 
 	case Open:
 		if time.Since(cb.lastFailureTime) >= cb.config.WaitDurationInOpenState {
-			cb.state.SetState(HalfOpen)
-			cb.halfOpenCalls = 0 // ✅ Reset half-open call count
+			t := cb.setState(HalfOpen)
+			transition = &t
+			cb.halfOpenCalls = 0     // ✅ Reset half-open call count
+			cb.halfOpenSuccesses = 0 // Reset probe successes
 			log.Printf("%s: moving state to half-open", cb.config.Name)
 		}
 
 	case HalfOpen:
-		if err != nil || isSlow { //Failure or slow call moves back to Open
-			cb.state.SetState(Open) // Move back to Open if a failure occurs
-			cb.halfOpenCalls = 0    // Reset half-open call count
+		if failed || isSlow { //Failure or slow call moves back to Open
+			t := cb.setState(Open) // Move back to Open if a failure occurs
+			transition = &t
+			cb.halfOpenCalls = 0     // Reset half-open call count
+			cb.halfOpenSuccesses = 0 // Reset probe successes
 			cb.lastFailureTime = time.Now()
 		} else {
-			//cb.halfOpenCalls++ // Count half-open calls when calls are being made, during decision it's quite late.
-			if cb.halfOpenCalls >= cb.config.PermittedNumberOfCallsInHalfOpenState {
-				// ✅ Move to CLOSED if all calls pass in Half-Open state
+			cb.halfOpenSuccesses++ // Count successful probes
+			if cb.halfOpenSuccesses >= cb.config.probeSuccessThreshold() {
+				// ✅ Move to CLOSED once enough probes have succeeded
 				log.Printf("%s: moving state to closed", cb.config.Name)
-				cb.state.SetState(Closed)
-				cb.halfOpenCalls = 0 //Reset half open calls
-				//cb.requests.Init()   // Let old failures naturally expire instead of wiping the history ✅
+				t := cb.setState(Closed)
+				transition = &t
+				cb.halfOpenCalls = 0     // Reset half open calls
+				cb.halfOpenSuccesses = 0 // Reset probe successes
+				// Let old failures naturally expire out of the window instead of wiping the history ✅
 			}
+			// Otherwise stay Half-Open; Execute rejects further calls once
+			// probeNumber() is reached without the threshold being met.
 		}
 	}
-}
-
-// addRequest tracks recent failures in a sliding window
-func (cb *CircuitBreaker) addRequest(failed, slow bool) {
-	// Ensure cb.requests is initialized
-	if cb.requests == nil {
-		cb.requests = list.New()
-	}
 
-	// Remove old requests based on the configured strategy
-	cb.cleanupOldRequests()
-
-	// Store boolean failure status in the sliding window
-	cb.requests.PushBack(requestEntry{failed: failed, slow: slow, executionTime: time.Now()})
+	cb.mu.Unlock()
 
-	if failed {
-		cb.failureCount++
-	} else if slow {
-		cb.slowCallCount++
-	}
+	// recordOutcome and notifyStateChange run user-supplied hooks and must
+	// not run while cb.mu is held (see notifyStateChange).
+	cb.recordOutcome(failed, isSlow)
+	cb.notifyStateChange(transition)
 }
 
-// cleanupOldRequests removes outdated requests based on the sliding window strategy
-func (cb *CircuitBreaker) cleanupOldRequests() {
-	switch cb.config.SlidingWindowType {
-	case COUNT_BASED:
-		cb.enforceCountBasedWindow()
-	case TIME_BASED:
-		cb.enforceTimeBasedWindow()
+// isFailure reports whether err should count as a failure for the purposes
+// of the failure rate, consulting Config.FailureCondition when configured.
+func (cb *CircuitBreaker[T]) isFailure(err error) bool {
+	if err == nil {
+		return false
 	}
+	if cb.config.FailureCondition != nil {
+		return cb.config.FailureCondition(err)
+	}
+	return true
 }
 
-// enforceCountBasedWindow removes oldest entries if count exceeds SlidingWindowSize
-func (cb *CircuitBreaker) enforceCountBasedWindow() {
-	for cb.requests.Len() >= cb.config.SlidingWindowSize {
-		if front := cb.requests.Front(); front != nil {
-			cb.removeFrontRequest()
+// recordOutcome notifies the configured metrics collector and On* hooks
+// about the outcome of a single call.
+func (cb *CircuitBreaker[T]) recordOutcome(failed, isSlow bool) {
+	if cb.config.Metrics != nil {
+		if failed {
+			cb.config.Metrics.IncFailure(cb.config.Name)
+		} else {
+			cb.config.Metrics.IncSuccess(cb.config.Name)
+		}
+		if isSlow {
+			cb.config.Metrics.IncSlowCall(cb.config.Name)
 		}
 	}
-}
 
-// enforceTimeBasedWindow removes entries older than SlidingWindowTime
-func (cb *CircuitBreaker) enforceTimeBasedWindow() {
-	expirationTime := time.Now().Add(-time.Duration(cb.config.SlidingWindowSize))
-
-	for cb.requests.Len() > 0 {
-		front := cb.requests.Front()
-		if front == nil {
-			break
+	if failed {
+		if cb.config.OnFailure != nil {
+			cb.config.OnFailure(cb.config.Name)
 		}
+	} else if cb.config.OnSuccess != nil {
+		cb.config.OnSuccess(cb.config.Name)
+	}
+}
 
-		entry := front.Value.(requestEntry)
-		if !entry.executionTime.Before(expirationTime) {
-			break // Stop removing when the first valid entry is found
-		}
+// Name returns the breaker's configured name.
+func (cb *CircuitBreaker[T]) Name() string {
+	return cb.config.Name
+}
 
-		cb.removeFrontRequest()
-	}
+// State returns the breaker's current state.
+func (cb *CircuitBreaker[T]) State() State {
+	return cb.state.Get()
 }
 
-// removeFrontRequest removes the front request and updates counters
-func (cb *CircuitBreaker) removeFrontRequest() {
-	front := cb.requests.Front()
-	if front != nil {
-		entry := front.Value.(requestEntry)
+// Counts returns the current window's total call count, failure count, and
+// slow-call count.
+func (cb *CircuitBreaker[T]) Counts() (total, failures, slow int) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.window.len(), cb.window.failureCount(), cb.window.slowCount()
+}
 
-		// Decrement counters accordingly
-		if entry.failed {
-			cb.failureCount--
-		} else if entry.slow {
-			cb.slowCallCount--
-		}
+// FailureRate returns the current failure rate as a percentage.
+func (cb *CircuitBreaker[T]) FailureRate() float64 {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.getFailureRate()
+}
 
-		cb.requests.Remove(front)
-	}
+// SlowCallRate returns the current slow-call rate as a percentage.
+func (cb *CircuitBreaker[T]) SlowCallRate() float64 {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.getSlowCallRate()
 }
 
-// getFailureRate returns the failure percentage in O(1) time
-func (cb *CircuitBreaker) getFailureRate() float64 {
-	if cb.requests.Len() == 0 {
+// getFailureRate returns the failure percentage over the current window
+func (cb *CircuitBreaker[T]) getFailureRate() float64 {
+	if cb.window.len() == 0 {
 		return 0.0
 	}
-	return (float64(cb.failureCount) / float64(cb.requests.Len())) * 100
+	return (float64(cb.window.failureCount()) / float64(cb.window.len())) * 100
 }
 
-// getSlowCallRate returns the slow call percentage in O(1) time
-func (cb *CircuitBreaker) getSlowCallRate() float64 {
-	if cb.requests.Len() == 0 {
+// getSlowCallRate returns the slow call percentage over the current window
+func (cb *CircuitBreaker[T]) getSlowCallRate() float64 {
+	if cb.window.len() == 0 {
 		return 0.0
 	}
-	return (float64(cb.slowCallCount) / float64(cb.requests.Len())) * 100
+	return (float64(cb.window.slowCount()) / float64(cb.window.len())) * 100
 }
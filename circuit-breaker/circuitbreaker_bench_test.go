@@ -0,0 +1,50 @@
+package circuit_breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// BenchmarkCountBasedWindow exercises the COUNT_BASED ring buffer, which
+// reuses a fixed-size slice and should report zero allocations per op.
+func BenchmarkCountBasedWindow(b *testing.B) {
+	config := Config{
+		Name:                      "bench-count",
+		SlidingWindowType:         COUNT_BASED,
+		FailureRateThreshold:      50,
+		MinimumNumberOfCalls:      1000,
+		SlidingWindowSize:         1000,
+		SlowCallDurationThreshold: time.Second,
+		SlowCallRateThreshold:     50.0,
+	}
+	breaker := NewCircuitBreaker[any](config)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = breaker.Execute(func() (any, error) { return nil, nil })
+	}
+}
+
+// BenchmarkTimeBasedWindow exercises the TIME_BASED bucketed ring buffer,
+// which keeps a fixed number of buckets regardless of call volume and
+// should likewise report zero allocations per op.
+func BenchmarkTimeBasedWindow(b *testing.B) {
+	config := Config{
+		Name:                      "bench-time",
+		SlidingWindowType:         TIME_BASED,
+		FailureRateThreshold:      50,
+		MinimumNumberOfCalls:      1000,
+		SlidingWindowTime:         time.Minute,
+		TimeBasedBuckets:          20,
+		SlowCallDurationThreshold: time.Second,
+		SlowCallRateThreshold:     50.0,
+	}
+	breaker := NewCircuitBreaker[any](config)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = breaker.Execute(func() (any, error) { return nil, nil })
+	}
+}
@@ -1,6 +1,7 @@
 package circuit_breaker
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -18,11 +19,11 @@ func TestHalfOpenToClosedTransition(t *testing.T) {
 		SlowCallDurationThreshold:             200 * time.Millisecond,
 		SlowCallRateThreshold:                 50.0,
 	}
-	breaker := NewCircuitBreaker(config)
+	breaker := NewCircuitBreaker[any](config)
 
 	// Step 1: Fail enough calls to trigger Open state
 	for i := 1; i <= 6; i++ {
-		_ = breaker.Execute(func() error { return errors.New("failure") })
+		_, _ = breaker.Execute(func() (any, error) { return nil, errors.New("failure") })
 	}
 
 	if breaker.state.state != Open {
@@ -33,15 +34,15 @@ func TestHalfOpenToClosedTransition(t *testing.T) {
 	time.Sleep(3 * time.Second)
 
 	//make success call to return to half open
-	breaker.Execute(func() error { return nil })
+	breaker.Execute(func() (any, error) { return nil, nil })
 
 	if breaker.state.state != HalfOpen {
 		t.Errorf("Expected circuit breaker to be HALF-OPEN, got %v", breaker.state.state)
 	}
 
 	// Step 3: Make permitted successful calls
-	breaker.Execute(func() error { return nil })
-	breaker.Execute(func() error { return nil }) // Last successful call in Half-Open
+	breaker.Execute(func() (any, error) { return nil, nil })
+	breaker.Execute(func() (any, error) { return nil, nil }) // Last successful call in Half-Open
 
 	if breaker.state.state != Closed {
 		t.Errorf("Expected circuit breaker to be CLOSED after successful Half-Open calls, got %v", breaker.state.state)
@@ -52,3 +53,334 @@ func TestHalfOpenToClosedTransition(t *testing.T) {
 		t.Errorf("Expected reset metrics, got halfOpenCalls=%d", breaker.halfOpenCalls)
 	}
 }
+
+func TestExecuteTypedResult(t *testing.T) {
+	config := Config{
+		Name:                  "typed",
+		SlidingWindowType:     COUNT_BASED,
+		FailureRateThreshold:  50,
+		MinimumNumberOfCalls:  4,
+		SlidingWindowSize:     10,
+		SlowCallRateThreshold: 50.0,
+	}
+	breaker := NewCircuitBreaker[int](config)
+
+	result, err := breaker.Execute(func() (int, error) { return 42, nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != 42 {
+		t.Errorf("Expected result 42, got %d", result)
+	}
+}
+
+func TestExecuteWithFallback(t *testing.T) {
+	config := Config{
+		Name:                  "fallback",
+		SlidingWindowType:     COUNT_BASED,
+		FailureRateThreshold:  50,
+		MinimumNumberOfCalls:  10,
+		SlidingWindowSize:     10,
+		SlowCallRateThreshold: 50.0,
+	}
+	breaker := NewCircuitBreaker[string](config)
+
+	result, err := breaker.ExecuteWithFallback(
+		func() (string, error) { return "", errors.New("boom") },
+		func(error) (string, error) { return "cached", nil },
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != "cached" {
+		t.Errorf("Expected fallback result %q, got %q", "cached", result)
+	}
+}
+
+func TestOnStateChangeHook(t *testing.T) {
+	var transitions []string
+	config := Config{
+		Name:                  "hooks",
+		SlidingWindowType:     COUNT_BASED,
+		FailureRateThreshold:  50,
+		MinimumNumberOfCalls:  2,
+		SlidingWindowSize:     10,
+		SlowCallRateThreshold: 50.0,
+		OnStateChange: func(name string, from, to State) {
+			transitions = append(transitions, string(from)+"->"+string(to))
+		},
+	}
+	breaker := NewCircuitBreaker[any](config)
+
+	for i := 0; i < 2; i++ {
+		_, _ = breaker.Execute(func() (any, error) { return nil, errors.New("failure") })
+	}
+
+	if len(transitions) != 1 || transitions[0] != "CLOSED->OPEN" {
+		t.Errorf("Expected a single CLOSED->OPEN transition, got %v", transitions)
+	}
+}
+
+// TestOnStateChangeHookCanReenterBreaker guards against a deadlock: the
+// state mutation happens under cb.mu, but hooks must run after it has been
+// released so a hook calling back into the same breaker doesn't block on
+// its own non-reentrant lock.
+func TestOnStateChangeHookCanReenterBreaker(t *testing.T) {
+	var breaker *CircuitBreaker[any]
+	config := Config{
+		Name:                  "reentrant",
+		SlidingWindowType:     COUNT_BASED,
+		FailureRateThreshold:  50,
+		MinimumNumberOfCalls:  1,
+		SlidingWindowSize:     10,
+		SlowCallRateThreshold: 50.0,
+		OnStateChange: func(name string, from, to State) {
+			if to == Open {
+				// Execute locks cb.mu; this would deadlock if the hook were
+				// still running with the outer call's lock held.
+				_, _ = breaker.Execute(func() (any, error) { return nil, nil })
+			}
+		},
+	}
+	breaker = NewCircuitBreaker[any](config)
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = breaker.Execute(func() (any, error) { return nil, errors.New("failure") })
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Execute did not return; OnStateChange likely deadlocked on cb.mu")
+	}
+}
+
+func TestOnRejectedHook(t *testing.T) {
+	rejected := 0
+	config := Config{
+		Name:                  "rejected",
+		SlidingWindowType:     COUNT_BASED,
+		FailureRateThreshold:  50,
+		MinimumNumberOfCalls:  1,
+		SlidingWindowSize:     10,
+		SlowCallRateThreshold: 50.0,
+		OnRejected: func(name string) {
+			rejected++
+		},
+	}
+	breaker := NewCircuitBreaker[any](config)
+
+	_, _ = breaker.Execute(func() (any, error) { return nil, errors.New("failure") })
+	_, _ = breaker.Execute(func() (any, error) { return nil, nil })
+
+	if rejected != 1 {
+		t.Errorf("Expected 1 rejected call, got %d", rejected)
+	}
+}
+
+func TestHalfOpenProbeSuccessThreshold(t *testing.T) {
+	config := Config{
+		Name:                      "probes",
+		SlidingWindowType:         COUNT_BASED,
+		FailureRateThreshold:      50,
+		MinimumNumberOfCalls:      6,
+		SlidingWindowSize:         10,
+		SlowCallDurationThreshold: time.Second,
+		SlowCallRateThreshold:     50.0,
+		ProbeNumber:               5,
+		ProbeSuccessThreshold:     2,
+	}
+	breaker := NewCircuitBreaker[any](config)
+	breaker.state.SetState(Open)
+	breaker.lastFailureTime = time.Now().Add(-time.Hour)
+
+	// First probe succeeds but shouldn't close the breaker yet: only 1 of 2
+	// required successes has happened.
+	if _, err := breaker.Execute(func() (any, error) { return nil, nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if breaker.state.state != HalfOpen {
+		t.Errorf("Expected HALF-OPEN after one probe success, got %v", breaker.state.state)
+	}
+
+	// Second successful probe reaches ProbeSuccessThreshold and closes,
+	// well before ProbeNumber permitted calls are exhausted.
+	if _, err := breaker.Execute(func() (any, error) { return nil, nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if breaker.state.state != Closed {
+		t.Errorf("Expected CLOSED after reaching ProbeSuccessThreshold, got %v", breaker.state.state)
+	}
+}
+
+func TestExecuteRejectionErrors(t *testing.T) {
+	config := Config{
+		Name:                    "typed-errors",
+		SlidingWindowType:       COUNT_BASED,
+		FailureRateThreshold:    50,
+		MinimumNumberOfCalls:    1,
+		SlidingWindowSize:       10,
+		SlowCallRateThreshold:   50.0,
+		WaitDurationInOpenState: time.Hour,
+	}
+	breaker := NewCircuitBreaker[any](config)
+
+	_, _ = breaker.Execute(func() (any, error) { return nil, errors.New("failure") })
+
+	_, err := breaker.Execute(func() (any, error) { return nil, nil })
+	if !errors.Is(err, ErrOpenState) {
+		t.Errorf("Expected ErrOpenState, got %v", err)
+	}
+}
+
+func TestExecuteContextCancellationIgnored(t *testing.T) {
+	config := Config{
+		Name:                      "ctx",
+		SlidingWindowType:         COUNT_BASED,
+		FailureRateThreshold:      50,
+		MinimumNumberOfCalls:      1,
+		SlidingWindowSize:         10,
+		SlowCallDurationThreshold: time.Hour,
+		SlowCallRateThreshold:     50.0,
+		FailureCondition:          IgnoreContextCancellation,
+	}
+	breaker := NewCircuitBreaker[any](config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Cancel mid-call so the action runs and its error reaches FailureCondition.
+	err := ExecuteContext(breaker, ctx, func(ctx context.Context) error {
+		cancel()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// A canceled context should not have been counted as a failure.
+	if breaker.state.state != Closed {
+		t.Errorf("Expected breaker to remain CLOSED after ignored cancellation, got %v", breaker.state.state)
+	}
+}
+
+func TestExecuteContextShortCircuitsOnDoneContext(t *testing.T) {
+	config := Config{
+		Name:                  "ctx-done",
+		SlidingWindowType:     COUNT_BASED,
+		FailureRateThreshold:  50,
+		MinimumNumberOfCalls:  1,
+		SlidingWindowSize:     10,
+		SlowCallRateThreshold: 50.0,
+	}
+	breaker := NewCircuitBreaker[any](config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := ExecuteContext(breaker, ctx, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if called {
+		t.Error("Expected action not to be called for an already-done context")
+	}
+}
+
+// fakeMetricsCollector records every MetricsCollector call it receives, for
+// asserting exactly which calls a CircuitBreaker makes and with what
+// arguments.
+type fakeMetricsCollector struct {
+	states           []string
+	stateTransitions []string
+	successes        int
+	failures         int
+	slowCalls        int
+	rejected         int
+	durations        int
+}
+
+func (f *fakeMetricsCollector) SetState(name string, state State) {
+	f.states = append(f.states, name+":"+string(state))
+}
+
+func (f *fakeMetricsCollector) IncStateTransition(name string, from, to State) {
+	f.stateTransitions = append(f.stateTransitions, name+":"+string(from)+"->"+string(to))
+}
+
+func (f *fakeMetricsCollector) IncSuccess(name string)  { f.successes++ }
+func (f *fakeMetricsCollector) IncFailure(name string)  { f.failures++ }
+func (f *fakeMetricsCollector) IncSlowCall(name string) { f.slowCalls++ }
+func (f *fakeMetricsCollector) IncRejected(name string) { f.rejected++ }
+func (f *fakeMetricsCollector) ObserveCallDuration(name string, duration time.Duration) {
+	f.durations++
+}
+
+func TestMetricsCollectorReceivesCallOutcomes(t *testing.T) {
+	metrics := &fakeMetricsCollector{}
+	config := Config{
+		Name:                      "metrics",
+		SlidingWindowType:         COUNT_BASED,
+		FailureRateThreshold:      50,
+		MinimumNumberOfCalls:      1,
+		SlidingWindowSize:         10,
+		SlowCallDurationThreshold: time.Hour,
+		SlowCallRateThreshold:     50.0,
+		WaitDurationInOpenState:   time.Hour,
+		Metrics:                   metrics,
+	}
+	breaker := NewCircuitBreaker[any](config)
+
+	_, _ = breaker.Execute(func() (any, error) { return nil, nil }) // success
+	if metrics.successes != 1 {
+		t.Errorf("expected 1 success, got %d", metrics.successes)
+	}
+
+	_, _ = breaker.Execute(func() (any, error) { return nil, errors.New("boom") }) // failure, trips Open
+	if metrics.failures != 1 {
+		t.Errorf("expected 1 failure, got %d", metrics.failures)
+	}
+	if len(metrics.stateTransitions) != 1 || metrics.stateTransitions[0] != "metrics:CLOSED->OPEN" {
+		t.Errorf("expected a single CLOSED->OPEN transition, got %v", metrics.stateTransitions)
+	}
+
+	_, _ = breaker.Execute(func() (any, error) { return nil, nil }) // rejected, breaker is Open
+	if metrics.rejected != 1 {
+		t.Errorf("expected 1 rejected call, got %d", metrics.rejected)
+	}
+
+	if metrics.durations != 2 {
+		t.Errorf("expected call duration observed for the 2 calls that ran, got %d", metrics.durations)
+	}
+	if len(metrics.states) == 0 {
+		t.Error("expected SetState to be called on transition")
+	}
+}
+
+func TestExecuteShim(t *testing.T) {
+	config := Config{
+		Name:                  "shim",
+		SlidingWindowType:     COUNT_BASED,
+		FailureRateThreshold:  50,
+		MinimumNumberOfCalls:  10,
+		SlidingWindowSize:     10,
+		SlowCallRateThreshold: 50.0,
+	}
+	breaker := NewCircuitBreaker[any](config)
+
+	called := false
+	if err := Execute(breaker, func() error {
+		called = true
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("Expected action to be called")
+	}
+}
@@ -22,4 +22,93 @@ type Config struct {
 	SlidingWindowType                     SlidingWindowType
 	SlowCallDurationThreshold             time.Duration
 	SlowCallRateThreshold                 float64
+
+	// TimeBasedBuckets sets how many fixed-duration buckets a TIME_BASED
+	// window is divided into (typically 10-60). When zero, defaultTimeBasedBuckets is used.
+	TimeBasedBuckets int
+
+	// ProbeNumber caps the number of calls permitted in Half-Open state. It
+	// takes priority over PermittedNumberOfCallsInHalfOpenState when set,
+	// letting the probe budget and the success threshold below be tuned
+	// independently; when zero, PermittedNumberOfCallsInHalfOpenState is
+	// used instead.
+	ProbeNumber int32
+	// ProbeSuccessThreshold is the number of successful Half-Open probes
+	// required before the breaker closes. When zero, it falls back to
+	// the effective ProbeNumber, preserving the historical behavior of
+	// closing once every permitted probe has succeeded.
+	ProbeSuccessThreshold int32
+
+	// FailureCondition classifies an action's error as a failure (true) or
+	// not (false) for the purposes of the failure rate. When nil, every
+	// non-nil error counts as a failure. Use IgnoreContextCancellation to
+	// exclude contexts canceled by the caller.
+	FailureCondition func(error) bool
+
+	// Metrics, when set, receives counters and gauges for every call and
+	// state transition. See MetricsCollector.
+	Metrics MetricsCollector
+
+	// OnStateChange is invoked whenever the breaker transitions between
+	// Closed, Open, and HalfOpen.
+	OnStateChange func(name string, from, to State)
+	// OnSuccess is invoked after a call that did not fail, including a slow one.
+	OnSuccess func(name string)
+	// OnFailure is invoked after a failed call.
+	OnFailure func(name string)
+	// OnRejected is invoked when a call is rejected without running,
+	// because the breaker is Open or the Half-Open probe budget is spent.
+	OnRejected func(name string)
+}
+
+// MetricsCollector receives counters and gauges describing a CircuitBreaker's
+// behavior, labelled by breaker name. Implementations must be safe for
+// concurrent use. See the promext subpackage for a Prometheus-backed
+// implementation.
+type MetricsCollector interface {
+	// SetState records the breaker's current state.
+	SetState(name string, state State)
+	// IncStateTransition records a transition between two states.
+	IncStateTransition(name string, from, to State)
+	// IncSuccess records a call that did not fail; a slow call that did not
+	// fail is recorded by both IncSuccess and IncSlowCall.
+	IncSuccess(name string)
+	// IncFailure records a failed call.
+	IncFailure(name string)
+	// IncSlowCall records a call that exceeded SlowCallDurationThreshold.
+	IncSlowCall(name string)
+	// IncRejected records a call rejected without running.
+	IncRejected(name string)
+	// ObserveCallDuration records how long a call took to run.
+	ObserveCallDuration(name string, duration time.Duration)
+}
+
+// probeNumber returns the number of calls permitted in Half-Open state,
+// preferring ProbeNumber and falling back to
+// PermittedNumberOfCallsInHalfOpenState when it is unset.
+func (c Config) probeNumber() int32 {
+	if c.ProbeNumber > 0 {
+		return c.ProbeNumber
+	}
+	return c.PermittedNumberOfCallsInHalfOpenState
+}
+
+// timeBasedBuckets returns the number of buckets a TIME_BASED window is
+// divided into, falling back to defaultTimeBasedBuckets when
+// TimeBasedBuckets is unset.
+func (c Config) timeBasedBuckets() int {
+	if c.TimeBasedBuckets > 0 {
+		return c.TimeBasedBuckets
+	}
+	return defaultTimeBasedBuckets
+}
+
+// probeSuccessThreshold returns the number of consecutive successful probes
+// required to close the breaker, falling back to probeNumber() when
+// ProbeSuccessThreshold is unset.
+func (c Config) probeSuccessThreshold() int32 {
+	if c.ProbeSuccessThreshold > 0 {
+		return c.ProbeSuccessThreshold
+	}
+	return c.probeNumber()
 }
@@ -0,0 +1,12 @@
+package circuit_breaker
+
+import "errors"
+
+// ErrOpenState is returned when a call is rejected because the breaker is
+// in the Open state. Use errors.Is to check for it, since it is typically
+// wrapped with the breaker's name for diagnostics.
+var ErrOpenState = errors.New("circuit breaker is open")
+
+// ErrTooManyRequests is returned when a call is rejected because the
+// breaker is Half-Open and has already used its permitted probe calls.
+var ErrTooManyRequests = errors.New("circuit breaker: too many requests in half-open state")
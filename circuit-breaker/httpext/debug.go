@@ -0,0 +1,45 @@
+package httpext
+
+import (
+	"encoding/json"
+	"net/http"
+
+	circuit_breaker "github.com/sinsuren/go-breaker/circuit-breaker"
+)
+
+// breakerStatus is the JSON shape DebugHandler emits for each breaker.
+type breakerStatus struct {
+	Name         string  `json:"name"`
+	State        string  `json:"state"`
+	Total        int     `json:"total"`
+	Failures     int     `json:"failures"`
+	SlowCalls    int     `json:"slow_calls"`
+	FailureRate  float64 `json:"failure_rate"`
+	SlowCallRate float64 `json:"slow_call_rate"`
+}
+
+// DebugHandler dumps the state, counts, and failure/slow rates of every
+// breaker returned by breakers as JSON, for ops visibility. Typically
+// mounted at a path like /breakers, e.g.:
+//
+//	mux.Handle("/breakers", httpext.DebugHandler(registry.Inspectable))
+func DebugHandler(breakers func() []circuit_breaker.Inspectable) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		statuses := make([]breakerStatus, 0, len(breakers()))
+		for _, cb := range breakers() {
+			total, failures, slow := cb.Counts()
+			statuses = append(statuses, breakerStatus{
+				Name:         cb.Name(),
+				State:        string(cb.State()),
+				Total:        total,
+				Failures:     failures,
+				SlowCalls:    slow,
+				FailureRate:  cb.FailureRate(),
+				SlowCallRate: cb.SlowCallRate(),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(statuses)
+	})
+}
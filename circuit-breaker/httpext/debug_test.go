@@ -0,0 +1,48 @@
+package httpext
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	circuit_breaker "github.com/sinsuren/go-breaker/circuit-breaker"
+)
+
+func TestDebugHandlerDumpsRegisteredBreakers(t *testing.T) {
+	registry := circuit_breaker.NewRegistry[any]()
+	cb := registry.GetOrCreate("widgets", newTestConfig())
+
+	_, _ = cb.Execute(func() (any, error) { return nil, nil })
+	_, _ = cb.Execute(func() (any, error) { return nil, errors.New("boom") })
+
+	handler := DebugHandler(registry.Inspectable)
+
+	req := httptest.NewRequest(http.MethodGet, "/breakers", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var statuses []breakerStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &statuses); err != nil {
+		t.Fatalf("failed to decode response body: %v", err)
+	}
+	if len(statuses) != 1 {
+		t.Fatalf("expected one breaker, got %d", len(statuses))
+	}
+
+	got := statuses[0]
+	if got.Name != "widgets" {
+		t.Errorf("expected name %q, got %q", "widgets", got.Name)
+	}
+	if got.Total != 2 {
+		t.Errorf("expected total 2, got %d", got.Total)
+	}
+	if got.Failures != 1 {
+		t.Errorf("expected 1 failure, got %d", got.Failures)
+	}
+}
@@ -0,0 +1,66 @@
+package httpext
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	circuit_breaker "github.com/sinsuren/go-breaker/circuit-breaker"
+)
+
+// Middleware wraps an http.Handler with a circuit breaker per route (keyed
+// by KeyFunc, which defaults to the request path), serving Fallback
+// instead of calling through while the breaker for that key is open.
+type Middleware struct {
+	Registry   *circuit_breaker.Registry[any]
+	Config     circuit_breaker.Config
+	KeyFunc    func(*http.Request) string
+	Classifier func(status int) bool
+	Fallback   http.Handler
+}
+
+// NewMiddleware builds a Middleware keyed by request path, treating 5xx
+// responses as failures and serving a 503 for rejected requests.
+func NewMiddleware(registry *circuit_breaker.Registry[any], config circuit_breaker.Config) *Middleware {
+	return &Middleware{
+		Registry:   registry,
+		Config:     config,
+		KeyFunc:    func(req *http.Request) string { return req.URL.Path },
+		Classifier: func(status int) bool { return status >= 500 },
+		Fallback: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "circuit breaker open", http.StatusServiceUnavailable)
+		}),
+	}
+}
+
+// Wrap returns next guarded by the middleware's circuit breaker.
+func (m *Middleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cb := m.Registry.GetOrCreate(m.KeyFunc(r), m.Config)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		_, err := cb.Execute(func() (any, error) {
+			next.ServeHTTP(rec, r)
+			if m.Classifier(rec.status) {
+				return nil, fmt.Errorf("handler returned status %d", rec.status)
+			}
+			return nil, nil
+		})
+
+		if err != nil && (errors.Is(err, circuit_breaker.ErrOpenState) || errors.Is(err, circuit_breaker.ErrTooManyRequests)) {
+			m.Fallback.ServeHTTP(w, r)
+		}
+	})
+}
+
+// statusRecorder captures the status code written by the wrapped handler
+// so Middleware can classify it without buffering the response body.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
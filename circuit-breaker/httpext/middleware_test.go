@@ -0,0 +1,44 @@
+package httpext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	circuit_breaker "github.com/sinsuren/go-breaker/circuit-breaker"
+)
+
+func TestMiddlewareServesFallbackWhenOpen(t *testing.T) {
+	registry := circuit_breaker.NewRegistry[any]()
+	config := circuit_breaker.Config{
+		SlidingWindowType:       circuit_breaker.COUNT_BASED,
+		FailureRateThreshold:    50,
+		MinimumNumberOfCalls:    1,
+		SlidingWindowSize:       10,
+		SlowCallRateThreshold:   50.0,
+		WaitDurationInOpenState: time.Hour,
+	}
+	mw := NewMiddleware(registry, config)
+
+	failing := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	handler := mw.Wrap(failing)
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+
+	// First call trips the breaker (5xx counts as a failure).
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected first call to reach the handler, got status %d", rec.Code)
+	}
+
+	// Second call should be rejected and served the fallback instead.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected fallback status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
@@ -0,0 +1,86 @@
+// Package httpext provides a Registry-backed http.RoundTripper and
+// http.Handler middleware for guarding outbound and inbound HTTP calls with
+// per-name circuit breakers.
+package httpext
+
+import (
+	"fmt"
+	"net/http"
+
+	circuit_breaker "github.com/sinsuren/go-breaker/circuit-breaker"
+)
+
+// ResponseClassifier reports whether resp should be treated as a
+// circuit-breaker failure. It only runs for round trips that completed
+// without a transport error — those already count as failures and are
+// never classified as anything else; to exclude a specific transport
+// error from the failure rate, set Config.FailureCondition instead (see
+// IgnoreContextCancellation for an example of the same mechanism).
+type ResponseClassifier func(resp *http.Response) bool
+
+// DefaultResponseClassifier treats 5xx responses as failures.
+func DefaultResponseClassifier(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode >= 500
+}
+
+// RoundTripper wraps Next with a circuit breaker per upstream (keyed by
+// KeyFunc, which defaults to the request host), rejecting requests while
+// the breaker for that key is open. A response classified as a failure by
+// Classifier still counts toward the breaker's failure rate, but the real
+// *http.Response is always handed back to the caller unchanged — only a
+// rejection by the breaker itself (ErrOpenState, ErrTooManyRequests) or a
+// transport error from Next surfaces as an error, matching normal
+// http.RoundTripper semantics.
+// Transport errors from Next always count as failures unless
+// Config.FailureCondition says otherwise.
+type RoundTripper struct {
+	Next       http.RoundTripper
+	Registry   *circuit_breaker.Registry[*http.Response]
+	Config     circuit_breaker.Config
+	KeyFunc    func(*http.Request) string
+	Classifier ResponseClassifier
+}
+
+// NewRoundTripper builds a RoundTripper keyed by request host, using
+// DefaultResponseClassifier. next may be nil, in which case
+// http.DefaultTransport is used.
+func NewRoundTripper(next http.RoundTripper, registry *circuit_breaker.Registry[*http.Response], config circuit_breaker.Config) *RoundTripper {
+	return &RoundTripper{
+		Next:       next,
+		Registry:   registry,
+		Config:     config,
+		KeyFunc:    func(req *http.Request) string { return req.URL.Host },
+		Classifier: DefaultResponseClassifier,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := rt.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	cb := rt.Registry.GetOrCreate(rt.KeyFunc(req), rt.Config)
+
+	resp, err := cb.Execute(func() (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err == nil && rt.Classifier(resp) {
+			// Keep the real response but also return a non-nil error so
+			// Execute's recordResult counts this call as a failure; resp
+			// itself is never discarded below.
+			err = fmt.Errorf("upstream %s returned %s", req.URL.Host, resp.Status)
+		}
+		return resp, err
+	})
+
+	// A completed round trip hands back the real response regardless of
+	// its status code — the synthetic error above exists only to drive the
+	// breaker's failure accounting. Only a rejection by the breaker itself
+	// or a transport error from next (resp is nil in both cases) surfaces
+	// as an error to the caller.
+	if resp != nil {
+		return resp, nil
+	}
+	return resp, err
+}
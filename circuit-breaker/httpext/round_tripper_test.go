@@ -0,0 +1,97 @@
+package httpext
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	circuit_breaker "github.com/sinsuren/go-breaker/circuit-breaker"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestConfig() circuit_breaker.Config {
+	return circuit_breaker.Config{
+		SlidingWindowType:         circuit_breaker.COUNT_BASED,
+		FailureRateThreshold:      50,
+		MinimumNumberOfCalls:      1,
+		SlidingWindowSize:         10,
+		SlowCallDurationThreshold: time.Second,
+		SlowCallRateThreshold:     50.0,
+		WaitDurationInOpenState:   time.Hour,
+	}
+}
+
+func TestRoundTripperTripsOn5xxResponses(t *testing.T) {
+	registry := circuit_breaker.NewRegistry[*http.Response]()
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusInternalServerError, Body: http.NoBody}, nil
+	})
+	rt := NewRoundTripper(next, registry, newTestConfig())
+
+	req := httptest.NewRequest(http.MethodGet, "http://upstream.example/widgets", nil)
+
+	// The 5xx counts as a failure and trips the breaker, but the real
+	// response is still handed back unchanged, matching normal
+	// http.RoundTripper semantics.
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("expected the real response, not an error, got %v", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	}
+
+	// Second call should be rejected by the now-open breaker rather than
+	// reaching next.
+	_, err = rt.RoundTrip(req)
+	if !errors.Is(err, circuit_breaker.ErrOpenState) {
+		t.Errorf("expected ErrOpenState, got %v", err)
+	}
+}
+
+func TestRoundTripperTripsOnTransportError(t *testing.T) {
+	registry := circuit_breaker.NewRegistry[*http.Response]()
+	wantErr := errors.New("connection refused")
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, wantErr
+	})
+	rt := NewRoundTripper(next, registry, newTestConfig())
+
+	req := httptest.NewRequest(http.MethodGet, "http://upstream.example/widgets", nil)
+
+	if _, err := rt.RoundTrip(req); !errors.Is(err, wantErr) {
+		t.Fatalf("expected the transport error to surface unchanged, got %v", err)
+	}
+
+	// The transport error counted as a failure and tripped the breaker.
+	_, err := rt.RoundTrip(req)
+	if !errors.Is(err, circuit_breaker.ErrOpenState) {
+		t.Errorf("expected ErrOpenState after the transport error tripped the breaker, got %v", err)
+	}
+}
+
+func TestRoundTripperPassesThroughSuccess(t *testing.T) {
+	registry := circuit_breaker.NewRegistry[*http.Response]()
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+	rt := NewRoundTripper(next, registry, newTestConfig())
+
+	req := httptest.NewRequest(http.MethodGet, "http://upstream.example/widgets", nil)
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,93 @@
+// Package promext provides a Prometheus-backed circuit_breaker.MetricsCollector.
+package promext
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	circuit_breaker "github.com/sinsuren/go-breaker/circuit-breaker"
+)
+
+var _ circuit_breaker.MetricsCollector = (*Collector)(nil)
+
+// Collector implements circuit_breaker.MetricsCollector, labelling every
+// metric by breaker name so a single Collector can back many breakers.
+type Collector struct {
+	state            *prometheus.GaugeVec
+	stateTransitions *prometheus.CounterVec
+	calls            *prometheus.CounterVec
+	callDuration     *prometheus.HistogramVec
+}
+
+// NewCollector builds a Collector and registers its metrics with reg.
+func NewCollector(reg prometheus.Registerer) *Collector {
+	c := &Collector{
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "circuit_breaker",
+			Name:      "state",
+			Help:      "Whether the breaker is currently in the given state (1) or not (0), labelled by breaker name and state.",
+		}, []string{"name", "state"}),
+		stateTransitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "circuit_breaker",
+			Name:      "state_transitions_total",
+			Help:      "Total number of state transitions, labelled by breaker name, source state, and destination state.",
+		}, []string{"name", "from", "to"}),
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "circuit_breaker",
+			Name:      "calls_total",
+			Help:      "Total number of calls, labelled by breaker name and outcome (success, failure, slow, rejected).",
+		}, []string{"name", "outcome"}),
+		callDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "circuit_breaker",
+			Name:      "call_duration_seconds",
+			Help:      "Duration of calls guarded by the circuit breaker, labelled by breaker name.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"name"}),
+	}
+
+	reg.MustRegister(c.state, c.stateTransitions, c.calls, c.callDuration)
+
+	return c
+}
+
+// SetState implements circuit_breaker.MetricsCollector.
+func (c *Collector) SetState(name string, state circuit_breaker.State) {
+	for _, s := range []circuit_breaker.State{circuit_breaker.Closed, circuit_breaker.Open, circuit_breaker.HalfOpen} {
+		value := 0.0
+		if s == state {
+			value = 1.0
+		}
+		c.state.WithLabelValues(name, string(s)).Set(value)
+	}
+}
+
+// IncStateTransition implements circuit_breaker.MetricsCollector.
+func (c *Collector) IncStateTransition(name string, from, to circuit_breaker.State) {
+	c.stateTransitions.WithLabelValues(name, string(from), string(to)).Inc()
+}
+
+// IncSuccess implements circuit_breaker.MetricsCollector.
+func (c *Collector) IncSuccess(name string) {
+	c.calls.WithLabelValues(name, "success").Inc()
+}
+
+// IncFailure implements circuit_breaker.MetricsCollector.
+func (c *Collector) IncFailure(name string) {
+	c.calls.WithLabelValues(name, "failure").Inc()
+}
+
+// IncSlowCall implements circuit_breaker.MetricsCollector.
+func (c *Collector) IncSlowCall(name string) {
+	c.calls.WithLabelValues(name, "slow").Inc()
+}
+
+// IncRejected implements circuit_breaker.MetricsCollector.
+func (c *Collector) IncRejected(name string) {
+	c.calls.WithLabelValues(name, "rejected").Inc()
+}
+
+// ObserveCallDuration implements circuit_breaker.MetricsCollector.
+func (c *Collector) ObserveCallDuration(name string, duration time.Duration) {
+	c.callDuration.WithLabelValues(name).Observe(duration.Seconds())
+}
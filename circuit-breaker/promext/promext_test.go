@@ -0,0 +1,99 @@
+package promext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	circuit_breaker "github.com/sinsuren/go-breaker/circuit-breaker"
+)
+
+func gaugeValue(t *testing.T, g prometheus.Gauge) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := g.Write(&m); err != nil {
+		t.Fatalf("failed to read gauge: %v", err)
+	}
+	return m.GetGauge().GetValue()
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatalf("failed to read counter: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestCollectorLabelsCallsByBreakerName(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := NewCollector(reg)
+
+	c.IncSuccess("orders")
+	c.IncFailure("orders")
+	c.IncFailure("payments")
+	c.IncSlowCall("orders")
+	c.IncRejected("payments")
+	c.ObserveCallDuration("orders", 10*time.Millisecond)
+
+	if got := counterValue(t, c.calls.WithLabelValues("orders", "success")); got != 1 {
+		t.Errorf("expected orders success count 1, got %v", got)
+	}
+	if got := counterValue(t, c.calls.WithLabelValues("orders", "failure")); got != 1 {
+		t.Errorf("expected orders failure count 1, got %v", got)
+	}
+	if got := counterValue(t, c.calls.WithLabelValues("payments", "failure")); got != 1 {
+		t.Errorf("expected payments failure count 1, got %v", got)
+	}
+	if got := counterValue(t, c.calls.WithLabelValues("orders", "slow")); got != 1 {
+		t.Errorf("expected orders slow count 1, got %v", got)
+	}
+	if got := counterValue(t, c.calls.WithLabelValues("payments", "rejected")); got != 1 {
+		t.Errorf("expected payments rejected count 1, got %v", got)
+	}
+}
+
+func TestCollectorSetStateTracksCurrentStateOnly(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := NewCollector(reg)
+
+	c.SetState("orders", circuit_breaker.Open)
+
+	if got := gaugeValue(t, c.state.WithLabelValues("orders", string(circuit_breaker.Open))); got != 1 {
+		t.Errorf("expected Open gauge 1, got %v", got)
+	}
+	if got := gaugeValue(t, c.state.WithLabelValues("orders", string(circuit_breaker.Closed))); got != 0 {
+		t.Errorf("expected Closed gauge 0, got %v", got)
+	}
+
+	c.IncStateTransition("orders", circuit_breaker.Closed, circuit_breaker.Open)
+	if got := counterValue(t, c.stateTransitions.WithLabelValues("orders", string(circuit_breaker.Closed), string(circuit_breaker.Open))); got != 1 {
+		t.Errorf("expected 1 CLOSED->OPEN transition, got %v", got)
+	}
+}
+
+func TestCollectorIntegratesWithCircuitBreaker(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	c := NewCollector(reg)
+
+	config := circuit_breaker.Config{
+		Name:                      "integration",
+		SlidingWindowType:         circuit_breaker.COUNT_BASED,
+		FailureRateThreshold:      50,
+		MinimumNumberOfCalls:      1,
+		SlidingWindowSize:         10,
+		SlowCallDurationThreshold: time.Hour,
+		SlowCallRateThreshold:     50.0,
+		Metrics:                   c,
+	}
+	breaker := circuit_breaker.NewCircuitBreaker[any](config)
+
+	_, _ = breaker.Execute(func() (any, error) { return nil, nil })
+
+	if got := counterValue(t, c.calls.WithLabelValues("integration", "success")); got != 1 {
+		t.Errorf("expected 1 success recorded through the breaker, got %v", got)
+	}
+}
@@ -0,0 +1,86 @@
+package circuit_breaker
+
+import "sync"
+
+// Registry owns a keyed set of CircuitBreaker[T] instances, created lazily
+// per name so that callers across a process (e.g. one breaker per upstream
+// host or route) can share breakers safely under concurrent access.
+type Registry[T any] struct {
+	mu       sync.RWMutex
+	breakers map[string]*CircuitBreaker[T]
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry[T any]() *Registry[T] {
+	return &Registry[T]{breakers: make(map[string]*CircuitBreaker[T])}
+}
+
+// GetOrCreate returns the breaker registered under name, creating it with
+// cfg (with cfg.Name set to name) if it doesn't exist yet.
+func (r *Registry[T]) GetOrCreate(name string, cfg Config) *CircuitBreaker[T] {
+	r.mu.RLock()
+	cb, ok := r.breakers[name]
+	r.mu.RUnlock()
+	if ok {
+		return cb
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cb, ok := r.breakers[name]; ok {
+		return cb
+	}
+
+	cfg.Name = name
+	cb = NewCircuitBreaker[T](cfg)
+	r.breakers[name] = cb
+	return cb
+}
+
+// Get returns the breaker registered under name, if any.
+func (r *Registry[T]) Get(name string) (*CircuitBreaker[T], bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cb, ok := r.breakers[name]
+	return cb, ok
+}
+
+// List returns every breaker currently registered.
+func (r *Registry[T]) List() []*CircuitBreaker[T] {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]*CircuitBreaker[T], 0, len(r.breakers))
+	for _, cb := range r.breakers {
+		list = append(list, cb)
+	}
+	return list
+}
+
+// Remove deletes the breaker registered under name, if any.
+func (r *Registry[T]) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.breakers, name)
+}
+
+// Inspectable exposes a CircuitBreaker's read-only status for ops tooling,
+// independent of its result type T.
+type Inspectable interface {
+	Name() string
+	State() State
+	Counts() (total, failures, slow int)
+	FailureRate() float64
+	SlowCallRate() float64
+}
+
+// Inspectable returns every registered breaker as an Inspectable, useful
+// for feeding ops tooling (such as httpext.DebugHandler) regardless of T.
+func (r *Registry[T]) Inspectable() []Inspectable {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]Inspectable, 0, len(r.breakers))
+	for _, cb := range r.breakers {
+		list = append(list, cb)
+	}
+	return list
+}
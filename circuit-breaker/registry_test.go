@@ -0,0 +1,39 @@
+package circuit_breaker
+
+import "testing"
+
+func TestRegistryGetOrCreateReusesBreaker(t *testing.T) {
+	registry := NewRegistry[any]()
+	config := Config{
+		SlidingWindowType:     COUNT_BASED,
+		FailureRateThreshold:  50,
+		MinimumNumberOfCalls:  10,
+		SlidingWindowSize:     10,
+		SlowCallRateThreshold: 50.0,
+	}
+
+	first := registry.GetOrCreate("upstream-a", config)
+	second := registry.GetOrCreate("upstream-a", config)
+
+	if first != second {
+		t.Error("Expected GetOrCreate to return the same breaker for the same name")
+	}
+	if first.Name() != "upstream-a" {
+		t.Errorf("Expected breaker name %q, got %q", "upstream-a", first.Name())
+	}
+}
+
+func TestRegistryRemove(t *testing.T) {
+	registry := NewRegistry[any]()
+	config := Config{SlidingWindowType: COUNT_BASED, SlidingWindowSize: 10}
+
+	registry.GetOrCreate("upstream-a", config)
+	registry.Remove("upstream-a")
+
+	if _, ok := registry.Get("upstream-a"); ok {
+		t.Error("Expected breaker to be removed")
+	}
+	if len(registry.List()) != 0 {
+		t.Errorf("Expected an empty registry, got %d breakers", len(registry.List()))
+	}
+}
@@ -15,6 +15,13 @@ type CircuitBreakerState struct {
 	mu    sync.Mutex
 }
 
+// Get returns the current state.
+func (s *CircuitBreakerState) Get() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
 func (s *CircuitBreakerState) SetState(newState State) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -0,0 +1,184 @@
+package circuit_breaker
+
+import "time"
+
+// defaultTimeBasedBuckets is used when Config.TimeBasedBuckets is unset.
+const defaultTimeBasedBuckets = 10
+
+// slidingWindow tracks failure/slow-call counts over a bounded window using
+// fixed-size, GC-free storage. countWindow backs COUNT_BASED breakers and
+// timeWindow backs TIME_BASED ones.
+type slidingWindow interface {
+	// record adds a single call outcome to the window.
+	record(failed, slow bool)
+	// len returns the number of calls currently represented in the window.
+	len() int
+	// failureCount returns the number of failed calls currently in the window.
+	failureCount() int
+	// slowCount returns the number of slow calls currently in the window
+	// that did not fail; a call that is both failed and slow counts only
+	// toward failureCount.
+	slowCount() int
+}
+
+// newSlidingWindow builds the window implementation selected by
+// config.SlidingWindowType.
+func newSlidingWindow(config Config) slidingWindow {
+	if config.SlidingWindowType == TIME_BASED {
+		return newTimeWindow(config.SlidingWindowTime, config.timeBasedBuckets())
+	}
+	return newCountWindow(config.SlidingWindowSize)
+}
+
+// countEntry is one slot of a countWindow's ring buffer.
+type countEntry struct {
+	valid  bool
+	failed bool
+	slow   bool
+}
+
+// countWindow is a fixed-size ring buffer of the last `capacity` request
+// outcomes, used for COUNT_BASED breakers. Writes overwrite the oldest
+// slot in place, so the window never allocates once warmed up.
+type countWindow struct {
+	entries  []countEntry
+	head     int
+	size     int
+	failures int
+	slows    int
+}
+
+func newCountWindow(capacity int) *countWindow {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &countWindow{entries: make([]countEntry, capacity)}
+}
+
+func (w *countWindow) record(failed, slow bool) {
+	slot := &w.entries[w.head]
+	if slot.valid {
+		if slot.failed {
+			w.failures--
+		} else if slot.slow {
+			w.slows--
+		}
+	} else {
+		w.size++
+	}
+
+	*slot = countEntry{valid: true, failed: failed, slow: slow}
+	if failed {
+		w.failures++
+	} else if slow {
+		w.slows++
+	}
+
+	w.head = (w.head + 1) % len(w.entries)
+}
+
+func (w *countWindow) len() int          { return w.size }
+func (w *countWindow) failureCount() int { return w.failures }
+func (w *countWindow) slowCount() int    { return w.slows }
+
+// bucket aggregates the calls recorded during one fixed-duration slice of
+// a timeWindow.
+type bucket struct {
+	start    time.Time
+	total    int
+	failures int
+	slow     int
+}
+
+// timeWindow divides the configured time window into a fixed number of
+// buckets arranged as a ring, keyed by wall-clock time. Each record call
+// clears any bucket that has aged out of the window (an O(N) scan over a
+// small, fixed N) before incrementing the bucket for "now", so memory is
+// bounded and the rate calculation never walks a list sized by traffic
+// volume.
+type timeWindow struct {
+	buckets     []bucket
+	bucketWidth time.Duration
+}
+
+func newTimeWindow(window time.Duration, numBuckets int) *timeWindow {
+	if numBuckets <= 0 {
+		numBuckets = defaultTimeBasedBuckets
+	}
+	width := window / time.Duration(numBuckets)
+	if width <= 0 {
+		width = time.Millisecond
+	}
+	return &timeWindow{
+		buckets:     make([]bucket, numBuckets),
+		bucketWidth: width,
+	}
+}
+
+func (w *timeWindow) record(failed, slow bool) {
+	now := time.Now()
+	w.expireStale(now)
+
+	b := w.currentBucket(now)
+	b.total++
+	if failed {
+		b.failures++
+	} else if slow {
+		b.slow++
+	}
+}
+
+// expireStale zeroes out any bucket whose slot no longer falls within the
+// trailing window, so a gap in traffic doesn't leave behind counts that
+// should no longer count toward the rate.
+func (w *timeWindow) expireStale(now time.Time) {
+	cutoff := now.Add(-w.bucketWidth * time.Duration(len(w.buckets)))
+	for i := range w.buckets {
+		if w.buckets[i].total > 0 && w.buckets[i].start.Before(cutoff) {
+			w.buckets[i] = bucket{}
+		}
+	}
+}
+
+// currentBucket returns the bucket for now's time slot, resetting it first
+// if it belongs to an earlier slot that has been reused. The slot number
+// and its ring index must both be derived from the same epoch (Unix time),
+// not mixed with time.Time.Truncate (which rounds relative to Go's zero
+// time) — otherwise the two fall out of alignment for bucket widths that
+// don't evenly divide the offset between the two epochs, wiping a bucket's
+// counts well before it has actually aged out.
+func (w *timeWindow) currentBucket(now time.Time) *bucket {
+	slot := now.UnixNano() / int64(w.bucketWidth)
+	slotStart := time.Unix(0, slot*int64(w.bucketWidth))
+	idx := int(slot % int64(len(w.buckets)))
+
+	b := &w.buckets[idx]
+	if !b.start.Equal(slotStart) {
+		*b = bucket{start: slotStart}
+	}
+	return b
+}
+
+func (w *timeWindow) len() int {
+	total := 0
+	for _, b := range w.buckets {
+		total += b.total
+	}
+	return total
+}
+
+func (w *timeWindow) failureCount() int {
+	total := 0
+	for _, b := range w.buckets {
+		total += b.failures
+	}
+	return total
+}
+
+func (w *timeWindow) slowCount() int {
+	total := 0
+	for _, b := range w.buckets {
+		total += b.slow
+	}
+	return total
+}
@@ -0,0 +1,62 @@
+package circuit_breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeWindowExpiresOldBuckets(t *testing.T) {
+	w := newTimeWindow(100*time.Millisecond, 5) // 20ms buckets
+
+	w.record(true, false)
+	if got := w.len(); got != 1 {
+		t.Fatalf("expected len 1 right after recording, got %d", got)
+	}
+
+	time.Sleep(150 * time.Millisecond) // outlives the whole 100ms window
+
+	w.record(false, false)
+	if got := w.len(); got != 1 {
+		t.Errorf("expected the stale failure to have expired, got len %d", got)
+	}
+	if got := w.failureCount(); got != 0 {
+		t.Errorf("expected failureCount 0 after expiry, got %d", got)
+	}
+}
+
+func TestCurrentBucketStableForOddBucketWidth(t *testing.T) {
+	w := newTimeWindow(70*time.Second, 10) // 7s buckets; doesn't evenly divide the Unix/zero-time offset
+
+	arbitrary := time.Date(2024, 1, 1, 0, 0, 3, 0, time.UTC)
+	slot := arbitrary.UnixNano() / int64(w.bucketWidth)
+	base := time.Unix(0, slot*int64(w.bucketWidth)) // start of the slot containing arbitrary
+
+	b1 := w.currentBucket(base)
+	b1.total++
+
+	b2 := w.currentBucket(base.Add(2 * time.Second)) // still within the same 7s slot
+	b2.total++
+
+	if b1 != b2 {
+		t.Fatalf("expected the same bucket to be reused within one bucket lifetime, got distinct buckets")
+	}
+	if got := b2.total; got != 2 {
+		t.Errorf("expected counts from both increments to survive, got total %d", got)
+	}
+}
+
+func TestCountWindowRingBufferOverwritesOldest(t *testing.T) {
+	w := newCountWindow(3)
+
+	w.record(true, false)  // failure, will be overwritten
+	w.record(false, false) // success
+	w.record(false, false) // success
+	w.record(false, false) // success, overwrites the first failure
+
+	if got := w.len(); got != 3 {
+		t.Fatalf("expected len to be capped at capacity 3, got %d", got)
+	}
+	if got := w.failureCount(); got != 0 {
+		t.Errorf("expected the overwritten failure to no longer be counted, got %d", got)
+	}
+}